@@ -0,0 +1,31 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+const (
+	// LabelScopeGlobal marks a label as visible/usable across all projects
+	LabelScopeGlobal = "g"
+	// LabelScopeProject marks a label as scoped to a single project
+	LabelScopeProject = "p"
+)
+
+const (
+	// ResourceTypeRepository marks a resource label binding as attached to a repository
+	ResourceTypeRepository = "repository"
+	// ResourceTypeTag marks a resource label binding as attached to a tag/artifact
+	ResourceTypeTag = "tag"
+	// ResourceTypeChart marks a resource label binding as attached to a chart
+	ResourceTypeChart = "chart"
+)
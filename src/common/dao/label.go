@@ -0,0 +1,136 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddLabel inserts a label record to the database and returns its ID
+func AddLabel(label *models.Label) (int64, error) {
+	o := GetOrmer()
+	return o.Insert(label)
+}
+
+// GetLabel returns the label specified by ID, it returns nil if the label
+// does not exist. Soft-deleted labels are hidden unless includeDeleted is
+// true, in which case the returned tombstone still carries Deleted/DeletedBy.
+func GetLabel(id int64, includeDeleted bool) (*models.Label, error) {
+	o := GetOrmer()
+	label := &models.Label{
+		ID: id,
+	}
+	if err := o.Read(label); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if label.Deleted && !includeDeleted {
+		return nil, nil
+	}
+	return label, nil
+}
+
+// GetLabelByNameAndScope returns the non-deleted label matching the given
+// name/scope(/project), used to detect name conflicts within the same scope
+func GetLabelByNameAndScope(name, scope string, projectID int64) (*models.Label, error) {
+	o := GetOrmer()
+	qs := o.QueryTable(&models.Label{}).Filter("Name", name).Filter("Scope", scope).Filter("Deleted", false)
+	if scope == "p" {
+		qs = qs.Filter("ProjectID", projectID)
+	}
+	labels := []*models.Label{}
+	if _, err := qs.All(&labels); err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels[0], nil
+}
+
+// UpdateLabel updates the name/description/color of an existing label
+func UpdateLabel(label *models.Label) error {
+	o := GetOrmer()
+	_, err := o.Update(label, "Name", "Description", "Color")
+	return err
+}
+
+// DeleteLabel soft-deletes the label specified by ID: rather than removing
+// the row it sets the deleted flag along with the deletion time and the
+// username of the user who deleted it, so existing resource label
+// bindings and the history trail keep referring to a valid label row
+func DeleteLabel(id int64, deletedBy string) error {
+	o := GetOrmer()
+	_, err := o.Update(&models.Label{
+		ID:          id,
+		Deleted:     true,
+		DeletedTime: time.Now().Format("2006-01-02 15:04:05"),
+		DeletedBy:   deletedBy,
+	}, "Deleted", "DeletedTime", "DeletedBy")
+	return err
+}
+
+// RestoreLabel clears the soft-delete markers of the label specified by ID
+func RestoreLabel(id int64) error {
+	o := GetOrmer()
+	_, err := o.Update(&models.Label{
+		ID:          id,
+		Deleted:     false,
+		DeletedTime: "",
+		DeletedBy:   "",
+	}, "Deleted", "DeletedTime", "DeletedBy")
+	return err
+}
+
+// ListLabels lists labels according to the query conditions
+func ListLabels(query *models.LabelQuery) ([]*models.Label, error) {
+	o := GetOrmer()
+	qs := o.QueryTable(&models.Label{})
+	if len(query.Scope) > 0 {
+		qs = qs.Filter("Scope", query.Scope)
+	}
+	if query.ProjectID > 0 {
+		qs = qs.Filter("ProjectID", query.ProjectID)
+	}
+	if len(query.Name) > 0 {
+		qs = qs.Filter("Name__icontains", query.Name)
+	}
+	if !query.IncludeDeleted {
+		qs = qs.Filter("Deleted", false)
+	}
+	if query.InUse {
+		usedIDs := []int64{}
+		if _, err := o.QueryTable(&models.ResourceLabel{}).Distinct().ValuesFlat(&usedIDs, "LabelID"); err != nil {
+			return nil, fmt.Errorf("failed to list in-use label IDs: %v", err)
+		}
+		if len(usedIDs) == 0 {
+			return []*models.Label{}, nil
+		}
+		qs = qs.Filter("ID__in", usedIDs)
+	}
+
+	labels := []*models.Label{}
+	_, err := qs.All(&labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %v", err)
+	}
+	return labels, nil
+}
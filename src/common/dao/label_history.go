@@ -0,0 +1,42 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddLabelHistory records a create/update/delete/restore event for a label
+func AddLabelHistory(history *models.LabelHistory) (int64, error) {
+	o := GetOrmer()
+	return o.Insert(history)
+}
+
+// ListLabelHistory returns the events recorded for a label, ordered oldest
+// first so callers can render them as a timeline
+func ListLabelHistory(labelID int64) ([]*models.LabelHistory, error) {
+	o := GetOrmer()
+	history := []*models.LabelHistory{}
+	_, err := o.QueryTable(&models.LabelHistory{}).
+		Filter("LabelID", labelID).
+		OrderBy("CreationTime").
+		All(&history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list label history: %v", err)
+	}
+	return history, nil
+}
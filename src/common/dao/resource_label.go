@@ -0,0 +1,98 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddResourceLabel binds a label to a resource, it returns the ID of the
+// binding record
+func AddResourceLabel(rl *models.ResourceLabel) (int64, error) {
+	o := GetOrmer()
+	return o.Insert(rl)
+}
+
+// GetResourceLabel returns the binding record specified by ID, it returns
+// nil if the record does not exist
+func GetResourceLabel(id int64) (*models.ResourceLabel, error) {
+	o := GetOrmer()
+	rl := &models.ResourceLabel{
+		ID: id,
+	}
+	if err := o.Read(rl); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rl, nil
+}
+
+// GetResourceLabelByLabelAndResource returns the binding record matching
+// the given label/resource pair, used to avoid binding the same label to
+// the same resource twice
+func GetResourceLabelByLabelAndResource(labelID int64, resourceID, resourceType string) (*models.ResourceLabel, error) {
+	o := GetOrmer()
+	rls := []*models.ResourceLabel{}
+	_, err := o.QueryTable(&models.ResourceLabel{}).
+		Filter("LabelID", labelID).
+		Filter("ResourceID", resourceID).
+		Filter("ResourceType", resourceType).
+		All(&rls)
+	if err != nil {
+		return nil, err
+	}
+	if len(rls) == 0 {
+		return nil, nil
+	}
+	return rls[0], nil
+}
+
+// DeleteResourceLabel removes the binding record specified by ID
+func DeleteResourceLabel(id int64) error {
+	o := GetOrmer()
+	_, err := o.Delete(&models.ResourceLabel{
+		ID: id,
+	})
+	return err
+}
+
+// ListResourceLabels lists resource label bindings according to the query
+// conditions. Passing LabelID lists the resources a label is attached to
+// (the reverse lookup), passing ResourceID/ResourceType lists the labels
+// attached to a resource
+func ListResourceLabels(query *models.ResourceLabelQuery) ([]*models.ResourceLabel, error) {
+	o := GetOrmer()
+	qs := o.QueryTable(&models.ResourceLabel{})
+	if query.LabelID > 0 {
+		qs = qs.Filter("LabelID", query.LabelID)
+	}
+	if len(query.ResourceID) > 0 {
+		qs = qs.Filter("ResourceID", query.ResourceID)
+	}
+	if len(query.ResourceType) > 0 {
+		qs = qs.Filter("ResourceType", query.ResourceType)
+	}
+
+	rls := []*models.ResourceLabel{}
+	if _, err := qs.All(&rls); err != nil {
+		return nil, fmt.Errorf("failed to list resource labels: %v", err)
+	}
+	return rls, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Label holds the details of a label, either global or scoped to a single
+// project, which can be bound to a repository, a tag/artifact or a chart
+// (see ResourceLabel).
+type Label struct {
+	ID           int64  `orm:"column(id)" json:"id"`
+	Name         string `orm:"column(name)" json:"name"`
+	Description  string `orm:"column(description)" json:"description"`
+	Color        string `orm:"column(color)" json:"color"`
+	Scope        string `orm:"column(scope)" json:"scope"`
+	ProjectID    int64  `orm:"column(project_id)" json:"project_id"`
+	CreationTime string `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   string `orm:"column(update_time);auto_now" json:"update_time"`
+	Deleted      bool   `orm:"column(deleted)" json:"deleted"`
+	DeletedTime  string `orm:"column(deleted_time)" json:"deleted_time,omitempty"`
+	DeletedBy    string `orm:"column(deleted_by)" json:"deleted_by,omitempty"`
+}
+
+// TableName is required by beego orm to map Label to table harbor_label
+func (l *Label) TableName() string {
+	return "harbor_label"
+}
+
+// LabelQuery holds the query conditions used to list labels, fields left
+// at their zero value are not applied as filters
+type LabelQuery struct {
+	Name      string
+	Scope     string
+	ProjectID int64
+	// InUse, when true, restricts the result to labels that are currently
+	// bound to at least one resource
+	InUse bool
+	// IncludeDeleted, when true, also returns soft-deleted labels
+	IncludeDeleted bool
+}
+
+// LabelHistory records a single create/update/delete/restore event applied
+// to a label, used to render the audit trail exposed via
+// GET /api/labels/{id}/history
+type LabelHistory struct {
+	ID           int64  `orm:"column(id)" json:"id"`
+	LabelID      int64  `orm:"column(label_id)" json:"label_id"`
+	Action       string `orm:"column(action)" json:"action"`
+	Username     string `orm:"column(username)" json:"username"`
+	Diff         string `orm:"column(diff)" json:"diff,omitempty"`
+	CreationTime string `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by beego orm to map LabelHistory to table harbor_label_history
+func (h *LabelHistory) TableName() string {
+	return "harbor_label_history"
+}
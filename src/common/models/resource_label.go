@@ -0,0 +1,39 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// ResourceLabel records a binding between a label and a resource (a
+// repository, a tag/artifact or a chart)
+type ResourceLabel struct {
+	ID           int64  `orm:"column(id)" json:"id"`
+	LabelID      int64  `orm:"column(label_id)" json:"label_id"`
+	ResourceID   string `orm:"column(resource_id)" json:"resource_id"`
+	ResourceType string `orm:"column(resource_type)" json:"resource_type"`
+	ProjectID    int64  `orm:"column(project_id)" json:"project_id"`
+	CreationTime string `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by beego orm to map ResourceLabel to table harbor_resource_label
+func (r *ResourceLabel) TableName() string {
+	return "harbor_resource_label"
+}
+
+// ResourceLabelQuery holds the query conditions used to list resource label
+// bindings, fields left at their zero value are not applied as filters
+type ResourceLabelQuery struct {
+	LabelID      int64
+	ResourceID   string
+	ResourceType string
+}
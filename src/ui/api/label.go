@@ -0,0 +1,620 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+const (
+	labelHistoryActionCreate  = "create"
+	labelHistoryActionUpdate  = "update"
+	labelHistoryActionDelete  = "delete"
+	labelHistoryActionRestore = "restore"
+)
+
+// LabelAPI handles request to /api/labels/{} and /api/labels/batch
+type LabelAPI struct {
+	BaseController
+	label *models.Label
+	id    int64
+}
+
+// Prepare resolves the label ID from the path for operations targeting a
+// single label; Post and Put decode the request body themselves once the
+// caller has been authenticated
+func (l *LabelAPI) Prepare() {
+	l.BaseController.Prepare()
+
+	method := l.Ctx.Request.Method
+	if method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete || method == http.MethodPost {
+		idStr := l.Ctx.Input.Param(":id")
+		if len(idStr) == 0 {
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil || id <= 0 {
+			l.HandleBadRequest("invalid label ID")
+			return
+		}
+		l.id = id
+	}
+}
+
+// checkPermission verifies the caller is allowed to create/update/delete a
+// label of the given scope/project, returns false (and has already written
+// the response) if access is denied
+func (l *LabelAPI) checkPermission(label *models.Label) bool {
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return false
+	}
+
+	if label.Scope == common.LabelScopeGlobal {
+		if !l.SecurityCtx.IsSysAdmin() {
+			l.HandleForbidden(l.SecurityCtx.GetUsername())
+			return false
+		}
+		return true
+	}
+
+	if !l.SecurityCtx.HasProjectAdminPermission(label.ProjectID) {
+		l.HandleForbidden(l.SecurityCtx.GetUsername())
+		return false
+	}
+	return true
+}
+
+// validate does the basic sanity checking shared by create/update: the
+// label must have a name and, if project scoped, must reference an
+// existing project
+func (l *LabelAPI) validate(label *models.Label) bool {
+	if len(label.Name) == 0 {
+		l.HandleBadRequest("label name can not be empty")
+		return false
+	}
+
+	if label.Scope != common.LabelScopeGlobal && label.Scope != common.LabelScopeProject {
+		l.HandleBadRequest("invalid label scope")
+		return false
+	}
+
+	if label.Scope == common.LabelScopeProject {
+		exist, err := l.ProjectMgr.Exists(label.ProjectID)
+		if err != nil {
+			l.HandleInternalServerError(err.Error())
+			return false
+		}
+		if !exist {
+			l.HandleNotFoundError("project not found")
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordLabelHistory appends a create/update/delete/restore event for a
+// label to its audit trail. The primary operation has already been
+// committed by the time this runs, so a failure here is logged rather
+// than failing the request back to the client
+func (l *LabelAPI) recordLabelHistory(labelID int64, action, diff string) {
+	if _, err := dao.AddLabelHistory(&models.LabelHistory{
+		LabelID:  labelID,
+		Action:   action,
+		Username: l.SecurityCtx.GetUsername(),
+		Diff:     diff,
+	}); err != nil {
+		log.Errorf("failed to record history for label %d: %v", labelID, err)
+	}
+}
+
+// fieldDiff returns a JSON object of the Name/Description/Color fields that
+// changed between before and after, in the form
+// {"field":{"from":...,"to":...}}
+func fieldDiff(before, after *models.Label) string {
+	type change struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	diff := map[string]change{}
+	if before.Name != after.Name {
+		diff["name"] = change{From: before.Name, To: after.Name}
+	}
+	if before.Description != after.Description {
+		diff["description"] = change{From: before.Description, To: after.Description}
+	}
+	if before.Color != after.Color {
+		diff["color"] = change{From: before.Color, To: after.Color}
+	}
+	if len(diff) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(diff)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Post creates a single label
+func (l *LabelAPI) Post() {
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+	l.label = &models.Label{}
+	l.DecodeJSONReq(l.label)
+	if !l.validate(l.label) {
+		return
+	}
+	if !l.checkPermission(l.label) {
+		return
+	}
+
+	existing, err := dao.GetLabelByNameAndScope(l.label.Name, l.label.Scope, l.label.ProjectID)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if existing != nil {
+		l.HandleConflict("label already exists")
+		return
+	}
+
+	id, err := dao.AddLabel(l.label)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.recordLabelHistory(id, labelHistoryActionCreate, fieldDiff(&models.Label{}, l.label))
+	l.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Get returns the label specified by the ID in the path. Soft-deleted
+// labels are hidden unless the caller is a system admin and passes
+// ?include_deleted=true, in which case the tombstone is returned
+func (l *LabelAPI) Get() {
+	includeDeleted, _ := l.GetBool("include_deleted", false)
+	if includeDeleted && !l.SecurityCtx.IsSysAdmin() {
+		includeDeleted = false
+	}
+
+	label, err := dao.GetLabel(l.id, includeDeleted)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+	l.Data["json"] = label
+	l.ServeJSON()
+}
+
+// GetResources returns the resources (repositories, tags/artifacts, charts)
+// that the label specified by the ID in the path is currently bound to,
+// the reverse of the per-resource label listing endpoints. Each binding
+// carries the ID of the project it was made in, so bindings the caller
+// can't read are filtered out rather than trusting the label's own scope
+func (l *LabelAPI) GetResources() {
+	label, err := dao.GetLabel(l.id, false)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+
+	resources, err := dao.ListResourceLabels(&models.ResourceLabelQuery{
+		LabelID: l.id,
+	})
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+
+	visible := []*models.ResourceLabel{}
+	for _, resource := range resources {
+		readable, err := l.canReadProject(resource.ProjectID)
+		if err != nil {
+			l.HandleInternalServerError(err.Error())
+			return
+		}
+		if readable {
+			visible = append(visible, resource)
+		}
+	}
+
+	l.Data["json"] = visible
+	l.ServeJSON()
+}
+
+// canReadProject reports whether the current caller may see resources that
+// belong to the given project: public projects are visible to everyone,
+// private ones require project read permission
+func (l *LabelAPI) canReadProject(projectID int64) (bool, error) {
+	project, err := l.ProjectMgr.Get(projectID)
+	if err != nil {
+		return false, err
+	}
+	if project == nil {
+		return false, nil
+	}
+	if project.IsPublic() {
+		return true, nil
+	}
+	return l.SecurityCtx.HasProjectReadPermission(projectID), nil
+}
+
+// GetHistory returns the ordered create/update/delete/restore events
+// recorded for the label specified by the ID in the path
+func (l *LabelAPI) GetHistory() {
+	label, err := dao.GetLabel(l.id, true)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+
+	history, err := dao.ListLabelHistory(l.id)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.Data["json"] = history
+	l.ServeJSON()
+}
+
+// Restore un-deletes the label specified by the ID in the path, only a
+// system admin may recover an accidentally deleted label
+func (l *LabelAPI) Restore() {
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+	if !l.SecurityCtx.IsSysAdmin() {
+		l.HandleForbidden(l.SecurityCtx.GetUsername())
+		return
+	}
+
+	label, err := dao.GetLabel(l.id, true)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+	if !label.Deleted {
+		l.HandleBadRequest("label is not deleted")
+		return
+	}
+
+	existing, err := dao.GetLabelByNameAndScope(label.Name, label.Scope, label.ProjectID)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if existing != nil {
+		l.HandleConflict("an active label with the same name already exists in this scope")
+		return
+	}
+
+	if err := dao.RestoreLabel(l.id); err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.recordLabelHistory(l.id, labelHistoryActionRestore, "")
+}
+
+// List returns the labels matching the scope/project_id/name query string
+func (l *LabelAPI) List() {
+	query := &models.LabelQuery{
+		Scope: l.GetString("scope"),
+		Name:  l.GetString("name"),
+	}
+
+	if query.Scope != common.LabelScopeGlobal && query.Scope != common.LabelScopeProject {
+		l.HandleBadRequest("scope must be either \"g\" or \"p\"")
+		return
+	}
+
+	if query.Scope == common.LabelScopeProject {
+		projectID, err := l.GetInt64("project_id")
+		if err != nil || projectID <= 0 {
+			l.HandleBadRequest("project_id must be specified for project scoped labels")
+			return
+		}
+		query.ProjectID = projectID
+	}
+
+	query.InUse, _ = l.GetBool("in_use", false)
+
+	labels, err := dao.ListLabels(query)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.Data["json"] = labels
+	l.ServeJSON()
+}
+
+// Put updates the label specified by the ID in the path
+func (l *LabelAPI) Put() {
+	label, err := dao.GetLabel(l.id, false)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+
+	if !l.checkPermission(label) {
+		return
+	}
+
+	l.label = &models.Label{}
+	l.DecodeJSONReq(l.label)
+	if len(l.label.Name) == 0 {
+		l.HandleBadRequest("label name can not be empty")
+		return
+	}
+
+	before := *label
+	label.Name = l.label.Name
+	label.Description = l.label.Description
+	label.Color = l.label.Color
+
+	if err := dao.UpdateLabel(label); err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.recordLabelHistory(label.ID, labelHistoryActionUpdate, fieldDiff(&before, label))
+}
+
+// Delete soft-deletes the label specified by the ID in the path. A second
+// delete of the same, already-deleted label reports 404 since GetLabel
+// hides soft-deleted rows from normal lookups
+func (l *LabelAPI) Delete() {
+	label, err := dao.GetLabel(l.id, false)
+	if err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		l.HandleNotFoundError("label not found")
+		return
+	}
+
+	if !l.checkPermission(label) {
+		return
+	}
+
+	if err := dao.DeleteLabel(l.id, l.SecurityCtx.GetUsername()); err != nil {
+		l.HandleInternalServerError(err.Error())
+		return
+	}
+	l.recordLabelHistory(l.id, labelHistoryActionDelete, "")
+}
+
+// labelBatchOp is a single operation within a POST /api/labels/batch request
+type labelBatchOp struct {
+	Action string        `json:"action"` // "create", "update" or "delete"
+	Label  *models.Label `json:"label"`
+}
+
+// labelBatchResult is the per-item outcome reported back for a batch request
+type labelBatchResult struct {
+	Index int    `json:"index"`
+	Code  int    `json:"code"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Batch applies a list of label create/update/delete operations in a single
+// request, each item is validated and authorized independently and the
+// response reports a status code per item (HTTP 207 Multi-Status) so
+// tooling syncing a large label taxonomy doesn't need one round-trip per
+// label
+func (l *LabelAPI) Batch() {
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+
+	ops := []*labelBatchOp{}
+	l.DecodeJSONReq(&ops)
+	if len(ops) == 0 {
+		l.HandleBadRequest("batch request must contain at least one operation")
+		return
+	}
+
+	results := make([]*labelBatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = l.applyBatchOp(i, op)
+	}
+
+	l.Ctx.Output.SetStatus(http.StatusMultiStatus)
+	l.Data["json"] = results
+	l.ServeJSON()
+}
+
+// applyBatchOp executes a single batch operation and never aborts the
+// request, errors are captured in the returned result instead
+func (l *LabelAPI) applyBatchOp(index int, op *labelBatchOp) *labelBatchResult {
+	result := &labelBatchResult{Index: index}
+
+	if op.Label == nil {
+		result.Code = http.StatusBadRequest
+		result.Error = "label can not be empty"
+		return result
+	}
+
+	switch op.Action {
+	case "create":
+		if !l.validateForBatch(op.Label, result) {
+			return result
+		}
+		if !l.canManageForBatch(op.Label) {
+			result.Code = http.StatusForbidden
+			result.Error = "forbidden"
+			return result
+		}
+		existing, err := dao.GetLabelByNameAndScope(op.Label.Name, op.Label.Scope, op.Label.ProjectID)
+		if err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		if existing != nil {
+			result.Code = http.StatusConflict
+			result.Error = "label already exists"
+			return result
+		}
+		id, err := dao.AddLabel(op.Label)
+		if err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		l.recordLabelHistory(id, labelHistoryActionCreate, fieldDiff(&models.Label{}, op.Label))
+		result.Code = http.StatusCreated
+		result.ID = id
+		return result
+
+	case "update":
+		label, err := dao.GetLabel(op.Label.ID, false)
+		if err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		if label == nil {
+			result.Code = http.StatusNotFound
+			result.Error = "label not found"
+			return result
+		}
+		if !l.canManageForBatch(label) {
+			result.Code = http.StatusForbidden
+			result.Error = "forbidden"
+			return result
+		}
+		if len(op.Label.Name) == 0 {
+			result.Code = http.StatusBadRequest
+			result.Error = "label name can not be empty"
+			return result
+		}
+		before := *label
+		label.Name = op.Label.Name
+		label.Description = op.Label.Description
+		label.Color = op.Label.Color
+		if err := dao.UpdateLabel(label); err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		l.recordLabelHistory(label.ID, labelHistoryActionUpdate, fieldDiff(&before, label))
+		result.Code = http.StatusOK
+		result.ID = label.ID
+		return result
+
+	case "delete":
+		label, err := dao.GetLabel(op.Label.ID, false)
+		if err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		if label == nil {
+			result.Code = http.StatusNotFound
+			result.Error = "label not found"
+			return result
+		}
+		if !l.checkPermission(label) {
+			result.Code = http.StatusForbidden
+			result.Error = "forbidden"
+			return result
+		}
+		if err := dao.DeleteLabel(label.ID, l.SecurityCtx.GetUsername()); err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return result
+		}
+		l.recordLabelHistory(label.ID, labelHistoryActionDelete, "")
+		result.Code = http.StatusOK
+		result.ID = label.ID
+		return result
+
+	default:
+		result.Code = http.StatusBadRequest
+		result.Error = "action must be one of \"create\", \"update\" or \"delete\""
+		return result
+	}
+}
+
+// canManageForBatch mirrors checkPermission() for an already authenticated
+// caller but only reports the outcome, leaving the response untouched
+func (l *LabelAPI) canManageForBatch(label *models.Label) bool {
+	if label.Scope == common.LabelScopeGlobal {
+		return l.SecurityCtx.IsSysAdmin()
+	}
+	return l.SecurityCtx.HasProjectAdminPermission(label.ProjectID)
+}
+
+// validateForBatch mirrors validate() but writes into a batch result
+// instead of the controller response
+func (l *LabelAPI) validateForBatch(label *models.Label, result *labelBatchResult) bool {
+	if len(label.Name) == 0 {
+		result.Code = http.StatusBadRequest
+		result.Error = "label name can not be empty"
+		return false
+	}
+	if label.Scope != common.LabelScopeGlobal && label.Scope != common.LabelScopeProject {
+		result.Code = http.StatusBadRequest
+		result.Error = "invalid label scope"
+		return false
+	}
+	if label.Scope == common.LabelScopeProject {
+		exist, err := l.ProjectMgr.Exists(label.ProjectID)
+		if err != nil {
+			result.Code = http.StatusInternalServerError
+			result.Error = err.Error()
+			return false
+		}
+		if !exist {
+			result.Code = http.StatusNotFound
+			result.Error = "project not found"
+			return false
+		}
+	}
+	return true
+}
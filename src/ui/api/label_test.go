@@ -433,3 +433,240 @@ func TestLabelAPIDelete(t *testing.T) {
 
 	runCodeCheckingCases(t, cases...)
 }
+
+func TestLabelAPIBatch(t *testing.T) {
+	labelBatchPath := fmt.Sprintf("%s/batch", labelAPIBasePath)
+
+	cases := []*codeCheckingCase{
+		// 401
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    labelBatchPath,
+				bodyJSON: []map[string]interface{}{
+					{
+						"action": "create",
+						"label": &models.Label{
+							Name:      "batch1",
+							Scope:     common.LabelScopeProject,
+							ProjectID: 1,
+						},
+					},
+				},
+			},
+			code: http.StatusUnauthorized,
+		},
+
+		// 400 empty batch
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        labelBatchPath,
+				bodyJSON:   []map[string]interface{}{},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+
+	// mixed 403/404/201 in a single batch, reported as 207
+	result := []*labelBatchResult{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    labelBatchPath,
+		bodyJSON: []map[string]interface{}{
+			// 201, created by a project admin
+			{
+				"action": "create",
+				"label": &models.Label{
+					Name:      "batch1",
+					Scope:     common.LabelScopeProject,
+					ProjectID: 1,
+				},
+			},
+			// 403, non-sysadmin can not create a global label
+			{
+				"action": "create",
+				"label": &models.Label{
+					Name:  "batch2",
+					Scope: common.LabelScopeGlobal,
+				},
+			},
+			// 404, project does not exist
+			{
+				"action": "create",
+				"label": &models.Label{
+					Name:      "batch3",
+					Scope:     common.LabelScopeProject,
+					ProjectID: 10000,
+				},
+			},
+		},
+		credential: projAdmin,
+	}, &result)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(result))
+	assert.Equal(t, http.StatusCreated, result[0].Code)
+	assert.Equal(t, http.StatusForbidden, result[1].Code)
+	assert.Equal(t, http.StatusNotFound, result[2].Code)
+
+	// clean up the label created above so it doesn't leak into other tests
+	err = handleAndParse(&testingRequest{
+		method: http.MethodPost,
+		url:    labelBatchPath,
+		bodyJSON: []map[string]interface{}{
+			{
+				"action": "delete",
+				"label": &models.Label{
+					ID: result[0].ID,
+				},
+			},
+		},
+		credential: projAdmin,
+	}, &result)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, http.StatusOK, result[0].Code)
+}
+
+func TestLabelAPIHistoryAndRestore(t *testing.T) {
+	// Post answers with the new ID in the Location header, not in the body
+	label := &models.Label{}
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPost,
+			url:    labelAPIBasePath,
+			bodyJSON: &models.Label{
+				Name:      "history",
+				Scope:     common.LabelScopeProject,
+				ProjectID: 1,
+			},
+			credential: projAdmin,
+		},
+		code: http.StatusCreated,
+		postFunc: func(resp *httptest.ResponseRecorder) error {
+			id, err := parseResourceID(resp)
+			if err != nil {
+				return err
+			}
+			label.ID = id
+			return nil
+		},
+	})
+	require.NotZero(t, label.ID)
+
+	err := handleAndParse(&testingRequest{
+		method: http.MethodPut,
+		url:    fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+		bodyJSON: &models.Label{
+			Name:      "history-renamed",
+			Scope:     common.LabelScopeProject,
+			ProjectID: 1,
+		},
+		credential: projAdmin,
+	}, nil)
+	require.Nil(t, err)
+
+	cases := []*codeCheckingCase{
+		// delete it
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+
+		// second delete of the already-deleted label: 404
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+
+		// plain GET no longer finds the deleted label
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+			},
+			code: http.StatusNotFound,
+		},
+
+		// a non-sysadmin passing include_deleted still can't see the tombstone
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+				queryStruct: struct {
+					IncludeDeleted bool `url:"include_deleted"`
+				}{
+					IncludeDeleted: true,
+				},
+				credential: nonSysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+
+		// restoring as a non-sysadmin is forbidden
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("%s/%d/restore", labelAPIBasePath, label.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	// a sysadmin passing include_deleted=true gets the tombstone back
+	tombstone := &models.Label{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+		queryStruct: struct {
+			IncludeDeleted bool `url:"include_deleted"`
+		}{
+			IncludeDeleted: true,
+		},
+		credential: sysAdmin,
+	}, tombstone)
+	require.Nil(t, err)
+	assert.True(t, tombstone.Deleted)
+	assert.NotEmpty(t, tombstone.DeletedBy)
+
+	// the audit trail records create, update and delete in order
+	history := []*models.LabelHistory{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/%d/history", labelAPIBasePath, label.ID),
+	}, &history)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(history))
+	assert.Equal(t, "create", history[0].Action)
+	assert.Equal(t, "update", history[1].Action)
+	assert.Equal(t, "delete", history[2].Action)
+
+	// a sysadmin can restore it
+	err = handleAndParse(&testingRequest{
+		method:     http.MethodPost,
+		url:        fmt.Sprintf("%s/%d/restore", labelAPIBasePath, label.ID),
+		credential: sysAdmin,
+	}, nil)
+	require.Nil(t, err)
+
+	restored := &models.Label{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/%d", labelAPIBasePath, label.ID),
+	}, restored)
+	require.Nil(t, err)
+	assert.False(t, restored.Deleted)
+}
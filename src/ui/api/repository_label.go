@@ -0,0 +1,211 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+)
+
+// resourceLabelRequest is the body of a bind-label-to-resource request
+type resourceLabelRequest struct {
+	LabelID int64 `json:"label_id"`
+}
+
+// RepositoryLabelAPI handles label binding requests on repositories and
+// tags/artifacts, i.e. /api/repositories/{repo}/labels and
+// /api/repositories/{repo}/tags/{tag}/labels
+type RepositoryLabelAPI struct {
+	BaseController
+	repository string
+	tag        string
+	project    *models.Project
+	req        *resourceLabelRequest
+	labelID    int64
+}
+
+// Prepare resolves the repository (and, if present, the tag) from the URL,
+// looks up the owning project so permission checks can be scoped to it,
+// and parses the label ID of an unbind request; Post decodes its request
+// body itself once the caller has been authorized
+func (r *RepositoryLabelAPI) Prepare() {
+	r.BaseController.Prepare()
+
+	r.repository = r.GetString(":splat")
+	r.tag = r.GetString(":tag")
+
+	projectName, _ := utils.ParseRepository(r.repository)
+	project, err := r.ProjectMgr.Get(projectName)
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+	if project == nil {
+		r.HandleNotFoundError(fmt.Sprintf("project %s not found", projectName))
+		return
+	}
+	r.project = project
+
+	method := r.Ctx.Request.Method
+	if method == http.MethodDelete {
+		id, err := strconv.ParseInt(r.GetString(":id"), 10, 64)
+		if err != nil || id <= 0 {
+			r.HandleBadRequest("invalid label ID")
+			return
+		}
+		r.labelID = id
+	}
+}
+
+// resourceID returns the identifier a binding is stored under: the
+// repository name itself, or "repo:tag" when scoped to a tag
+func (r *RepositoryLabelAPI) resourceID() string {
+	if len(r.tag) == 0 {
+		return r.repository
+	}
+	return fmt.Sprintf("%s:%s", r.repository, r.tag)
+}
+
+func (r *RepositoryLabelAPI) resourceType() string {
+	if len(r.tag) == 0 {
+		return common.ResourceTypeRepository
+	}
+	return common.ResourceTypeTag
+}
+
+// Post binds a label to the repository or tag resolved in Prepare
+func (r *RepositoryLabelAPI) Post() {
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+	if !r.SecurityCtx.HasProjectAdminPermission(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	r.req = &resourceLabelRequest{}
+	r.DecodeJSONReq(r.req)
+	if r.req.LabelID <= 0 {
+		r.HandleBadRequest("label_id must be specified")
+		return
+	}
+
+	label, err := dao.GetLabel(r.req.LabelID, false)
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+	if label == nil {
+		r.HandleNotFoundError("label not found")
+		return
+	}
+	if label.Scope == common.LabelScopeProject && label.ProjectID != r.project.ProjectID {
+		r.HandleBadRequest("label does not belong to the project of the target resource")
+		return
+	}
+
+	existing, err := dao.GetResourceLabelByLabelAndResource(label.ID, r.resourceID(), r.resourceType())
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+	if existing != nil {
+		r.HandleConflict("label already bound to this resource")
+		return
+	}
+
+	id, err := dao.AddResourceLabel(&models.ResourceLabel{
+		LabelID:      label.ID,
+		ResourceID:   r.resourceID(),
+		ResourceType: r.resourceType(),
+		ProjectID:    r.project.ProjectID,
+	})
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+	r.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// List returns the labels currently bound to the repository or tag
+// resolved in Prepare
+func (r *RepositoryLabelAPI) List() {
+	if !r.project.IsPublic() && !r.SecurityCtx.HasProjectReadPermission(r.project.ProjectID) {
+		if !r.SecurityCtx.IsAuthenticated() {
+			r.HandleUnauthorized()
+			return
+		}
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	bindings, err := dao.ListResourceLabels(&models.ResourceLabelQuery{
+		ResourceID:   r.resourceID(),
+		ResourceType: r.resourceType(),
+	})
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+
+	labels := []*models.Label{}
+	for _, binding := range bindings {
+		label, err := dao.GetLabel(binding.LabelID, false)
+		if err != nil {
+			r.HandleInternalServerError(err.Error())
+			return
+		}
+		if label != nil {
+			labels = append(labels, label)
+		}
+	}
+	r.Data["json"] = labels
+	r.ServeJSON()
+}
+
+// Delete unbinds the label specified by the ID in the path from the
+// repository or tag resolved in Prepare
+func (r *RepositoryLabelAPI) Delete() {
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+	if !r.SecurityCtx.HasProjectAdminPermission(r.project.ProjectID) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	binding, err := dao.GetResourceLabelByLabelAndResource(r.labelID, r.resourceID(), r.resourceType())
+	if err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+	if binding == nil {
+		r.HandleNotFoundError("label is not bound to this resource")
+		return
+	}
+
+	if err := dao.DeleteResourceLabel(binding.ID); err != nil {
+		r.HandleInternalServerError(err.Error())
+		return
+	}
+}
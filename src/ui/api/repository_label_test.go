@@ -0,0 +1,180 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestRepositoryLabelAPIPostAndList(t *testing.T) {
+	repository := "library/photon"
+
+	// a project label owned by project 1 to bind to the repository above;
+	// Post answers with the new ID in the Location header, not in the body
+	label := &models.Label{}
+	runCodeCheckingCases(t, &codeCheckingCase{
+		request: &testingRequest{
+			method: http.MethodPost,
+			url:    labelAPIBasePath,
+			bodyJSON: &models.Label{
+				Name:      "release",
+				Scope:     common.LabelScopeProject,
+				ProjectID: 1,
+			},
+			credential: projAdmin,
+		},
+		code: http.StatusCreated,
+		postFunc: func(resp *httptest.ResponseRecorder) error {
+			id, err := parseResourceID(resp)
+			if err != nil {
+				return err
+			}
+			label.ID = id
+			return nil
+		},
+	})
+	require.NotZero(t, label.ID)
+
+	cases := []*codeCheckingCase{
+		// 401
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+			},
+			code: http.StatusUnauthorized,
+		},
+
+		// 403 developer can not bind a label
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+				bodyJSON: &resourceLabelRequest{
+					LabelID: label.ID,
+				},
+				credential: projDeveloper,
+			},
+			code: http.StatusForbidden,
+		},
+
+		// 400 label_id missing
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        fmt.Sprintf("/api/repositories/%s/labels", repository),
+				bodyJSON:   &resourceLabelRequest{},
+				credential: projAdmin,
+			},
+			code: http.StatusBadRequest,
+		},
+
+		// 404 label does not exist
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+				bodyJSON: &resourceLabelRequest{
+					LabelID: 10000,
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+
+		// 201
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+				bodyJSON: &resourceLabelRequest{
+					LabelID: label.ID,
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusCreated,
+		},
+
+		// 409 already bound
+		&codeCheckingCase{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+				bodyJSON: &resourceLabelRequest{
+					LabelID: label.ID,
+				},
+				credential: projAdmin,
+			},
+			code: http.StatusConflict,
+		},
+	}
+	runCodeCheckingCases(t, cases...)
+
+	labels := []*models.Label{}
+	err := handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    fmt.Sprintf("/api/repositories/%s/labels", repository),
+	}, &labels)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(labels))
+	assert.Equal(t, label.ID, labels[0].ID)
+
+	inUse := []*models.Label{}
+	err = handleAndParse(&testingRequest{
+		method: http.MethodGet,
+		url:    labelAPIBasePath,
+		queryStruct: struct {
+			Scope     string `url:"scope"`
+			ProjectID int64  `url:"project_id"`
+			InUse     bool   `url:"in_use"`
+		}{
+			Scope:     "p",
+			ProjectID: 1,
+			InUse:     true,
+		},
+	}, &inUse)
+	require.Nil(t, err)
+	assert.True(t, len(inUse) >= 1)
+
+	// 200, unbind
+	deleteCase := []*codeCheckingCase{
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("/api/repositories/%s/labels/%d", repository, label.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 404 second unbind
+		&codeCheckingCase{
+			request: &testingRequest{
+				method:     http.MethodDelete,
+				url:        fmt.Sprintf("/api/repositories/%s/labels/%d", repository, label.ID),
+				credential: projAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+	}
+	runCodeCheckingCases(t, deleteCase...)
+}
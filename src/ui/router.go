@@ -0,0 +1,35 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/vmware/harbor/src/ui/api"
+)
+
+// initRouters registers the REST API route table
+func initRouters() {
+	beego.Router("/api/labels", &api.LabelAPI{}, "get:List;post:Post")
+	beego.Router("/api/labels/batch", &api.LabelAPI{}, "post:Batch")
+	beego.Router("/api/labels/:id([0-9]+)", &api.LabelAPI{}, "get:Get;put:Put;delete:Delete")
+	beego.Router("/api/labels/:id([0-9]+)/resources", &api.LabelAPI{}, "get:GetResources")
+	beego.Router("/api/labels/:id([0-9]+)/history", &api.LabelAPI{}, "get:GetHistory")
+	beego.Router("/api/labels/:id([0-9]+)/restore", &api.LabelAPI{}, "post:Restore")
+
+	beego.Router("/api/repositories/*/labels", &api.RepositoryLabelAPI{}, "get:List;post:Post")
+	beego.Router("/api/repositories/*/labels/:id([0-9]+)", &api.RepositoryLabelAPI{}, "delete:Delete")
+	beego.Router("/api/repositories/*/tags/:tag/labels", &api.RepositoryLabelAPI{}, "get:List;post:Post")
+	beego.Router("/api/repositories/*/tags/:tag/labels/:id([0-9]+)", &api.RepositoryLabelAPI{}, "delete:Delete")
+}